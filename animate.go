@@ -0,0 +1,49 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"io"
+)
+
+// encodeAnimated assembles frames into an animated GIF. Every frame is
+// dithered (Floyd-Steinberg) against one palette derived from the
+// colors used across all frames, so colors stay consistent from frame
+// to frame instead of shifting as each frame picks its own palette.
+func encodeAnimated(w io.Writer, frames []*image.RGBA, delay, loopCount int) error {
+	palette := sharedPalette(frames)
+
+	g := &gif.GIF{LoopCount: loopCount}
+	for _, frame := range frames {
+		paletted := image.NewPaletted(frame.Bounds(), palette)
+		draw.FloydSteinberg.Draw(paletted, frame.Bounds(), frame, image.Point{})
+		g.Image = append(g.Image, paletted)
+		g.Delay = append(g.Delay, delay)
+	}
+	return gif.EncodeAll(w, g)
+}
+
+// sharedPalette collects up to 256 distinct colors used across frames.
+// layoutimg images are flat-colored tile grids, so this rarely comes
+// close to the GIF palette limit in practice.
+func sharedPalette(frames []*image.RGBA) color.Palette {
+	white := color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	palette := color.Palette{white}
+	seen := map[color.RGBA]bool{white: true}
+
+	for _, frame := range frames {
+		b := frame.Bounds()
+		for y := b.Min.Y; y < b.Max.Y && len(palette) < 256; y++ {
+			for x := b.Min.X; x < b.Max.X && len(palette) < 256; x++ {
+				c := frame.RGBAAt(x, y)
+				if !seen[c] {
+					seen[c] = true
+					palette = append(palette, c)
+				}
+			}
+		}
+	}
+	return palette
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jiro4989/layoutimg/layoutimg"
+	"golang.org/x/sys/unix"
+)
+
+// ansiRenderer prints a grid to the terminal using 24-bit ANSI escapes
+// and the half-block trick (▄ with distinct fg/bg colors), so each
+// terminal row represents two pixel rows. It reuses the same rendering
+// pipeline as the raster/vector Renderers, just swapping the encoder.
+type ansiRenderer struct {
+	// columns is the target terminal width; 0 auto-detects it.
+	columns int
+}
+
+func (r ansiRenderer) Render(w io.Writer, grid *layoutimg.Grid) error {
+	columns := r.columns
+	if columns <= 0 {
+		columns = terminalWidth()
+	}
+	img := downsample(grid.Image(), columns)
+	return writeANSI(w, img)
+}
+
+// terminalWidth returns the current terminal's column count, falling
+// back to 80 when stdout isn't a terminal or the ioctl fails.
+func terminalWidth() int {
+	ws, err := unix.IoctlGetWinsize(int(os.Stdout.Fd()), unix.TIOCGWINSZ)
+	if err != nil || ws.Col == 0 {
+		return 80
+	}
+	return int(ws.Col)
+}
+
+// downsample nearest-neighbor resizes img to width columns, keeping its
+// aspect ratio and rounding the resulting height up to an even number
+// so rows pair up cleanly for the half-block trick.
+func downsample(img image.Image, width int) *image.RGBA {
+	if width <= 0 {
+		width = 80
+	}
+	b := img.Bounds()
+	srcW, srcH := b.Dx(), b.Dy()
+
+	height := srcH * width / srcW
+	if height == 0 {
+		height = 2
+	}
+	if height%2 != 0 {
+		height++
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		sy := b.Min.Y + y*srcH/height
+		for x := 0; x < width; x++ {
+			sx := b.Min.X + x*srcW/width
+			dst.Set(x, y, img.At(sx, sy))
+		}
+	}
+	return dst
+}
+
+// writeANSI prints img as rows of half-block characters, one character
+// per pixel column and two source rows per character row.
+func writeANSI(w io.Writer, img *image.RGBA) error {
+	b := img.Bounds()
+	var buf strings.Builder
+	for y := b.Min.Y; y < b.Max.Y; y += 2 {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			top := img.RGBAAt(x, y)
+			bottom := img.RGBAAt(x, y+1)
+			fmt.Fprintf(&buf, "\x1b[38;2;%d;%d;%dm\x1b[48;2;%d;%d;%dm▄",
+				top.R, top.G, top.B, bottom.R, bottom.G, bottom.B)
+		}
+		buf.WriteString("\x1b[0m\n")
+	}
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
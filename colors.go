@@ -0,0 +1,27 @@
+package main
+
+import "image/color"
+
+// colors maps the color names accepted by -b/-s/-f and <args>'s COLOR
+// field to their RGBA values. parseColor falls back to a literal
+// 'r,g,b' triplet for anything not listed here.
+//
+// "none" is the zero color.RGBA{}, the sentinel layoutimg/draw.go
+// checks to skip filling a shape entirely.
+var colors = map[string]color.RGBA{
+	"none":    {},
+	"white":   {R: 255, G: 255, B: 255, A: 255},
+	"black":   {A: 255},
+	"red":     {R: 255, A: 255},
+	"green":   {G: 255, A: 255},
+	"blue":    {B: 255, A: 255},
+	"yellow":  {R: 255, G: 255, A: 255},
+	"cyan":    {G: 255, B: 255, A: 255},
+	"magenta": {R: 255, B: 255, A: 255},
+	"gray":    {R: 128, G: 128, B: 128, A: 255},
+	"grey":    {R: 128, G: 128, B: 128, A: 255},
+	"orange":  {R: 255, G: 165, A: 255},
+	"purple":  {R: 128, B: 128, A: 255},
+	"brown":   {R: 165, G: 42, B: 42, A: 255},
+	"pink":    {R: 255, G: 192, B: 203, A: 255},
+}
@@ -0,0 +1,176 @@
+package layoutimg
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// paintShape fills r according to style.Shape.
+func paintShape(dst *image.RGBA, r image.Rectangle, style Style) {
+	switch style.Shape {
+	case ShapeEllipse:
+		fillEllipse(dst, r, style)
+	case ShapeDiamond:
+		fillDiamond(dst, r, style)
+	case ShapeRoundedRect:
+		fillRoundedRect(dst, r, style)
+	default:
+		fillRect(dst, r, style)
+	}
+}
+
+// fillRect fills r with style.FillColor and strokes its border with
+// style.StrokeColor/style.LineWidth.
+func fillRect(dst *image.RGBA, r image.Rectangle, style Style) {
+	if (style.FillColor != color.RGBA{}) {
+		draw.Draw(dst, r, &image.Uniform{style.FillColor}, image.Point{}, draw.Src)
+	}
+	strokeRect(dst, r, style)
+}
+
+// strokeRect paints the border of r, style.LineWidth pixels thick.
+func strokeRect(dst *image.RGBA, r image.Rectangle, style Style) {
+	if style.LineWidth <= 0 {
+		return
+	}
+	w := int(math.Round(style.LineWidth))
+	sides := []image.Rectangle{
+		image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+w),
+		image.Rect(r.Min.X, r.Max.Y-w, r.Max.X, r.Max.Y),
+		image.Rect(r.Min.X, r.Min.Y, r.Min.X+w, r.Max.Y),
+		image.Rect(r.Max.X-w, r.Min.Y, r.Max.X, r.Max.Y),
+	}
+	for _, side := range sides {
+		draw.Draw(dst, side, &image.Uniform{style.StrokeColor}, image.Point{}, draw.Src)
+	}
+}
+
+// fillEllipse fills the ellipse inscribed in r with style.FillColor and
+// strokes r's border with style.StrokeColor/style.LineWidth.
+func fillEllipse(dst *image.RGBA, r image.Rectangle, style Style) {
+	if (style.FillColor != color.RGBA{}) {
+		cx, cy := float64(r.Min.X+r.Max.X)/2, float64(r.Min.Y+r.Max.Y)/2
+		rx, ry := float64(r.Dx())/2, float64(r.Dy())/2
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				dx, dy := (float64(x)-cx)/rx, (float64(y)-cy)/ry
+				if dx*dx+dy*dy <= 1 {
+					dst.Set(x, y, style.FillColor)
+				}
+			}
+		}
+	}
+	strokeRect(dst, r, style)
+}
+
+// fillDiamond fills the diamond inscribed in r with style.FillColor and
+// strokes r's border with style.StrokeColor/style.LineWidth.
+func fillDiamond(dst *image.RGBA, r image.Rectangle, style Style) {
+	if (style.FillColor != color.RGBA{}) {
+		cx, cy := float64(r.Min.X+r.Max.X)/2, float64(r.Min.Y+r.Max.Y)/2
+		rx, ry := float64(r.Dx())/2, float64(r.Dy())/2
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				dx, dy := math.Abs(float64(x)-cx)/rx, math.Abs(float64(y)-cy)/ry
+				if dx+dy <= 1 {
+					dst.Set(x, y, style.FillColor)
+				}
+			}
+		}
+	}
+	strokeRect(dst, r, style)
+}
+
+// fillRoundedRect fills r, with its four corners rounded to a radius of
+// a quarter of its shorter side, with style.FillColor, and strokes r's
+// border with style.StrokeColor/style.LineWidth.
+func fillRoundedRect(dst *image.RGBA, r image.Rectangle, style Style) {
+	if (style.FillColor != color.RGBA{}) {
+		radius := float64(minInt(r.Dx(), r.Dy())) / 4
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				if inRoundedRect(x, y, r, radius) {
+					dst.Set(x, y, style.FillColor)
+				}
+			}
+		}
+	}
+	strokeRect(dst, r, style)
+}
+
+// inRoundedRect reports whether (x, y) lies within r once its four
+// corners are rounded to radius.
+func inRoundedRect(x, y int, r image.Rectangle, radius float64) bool {
+	nearLeft := float64(x) < float64(r.Min.X)+radius
+	nearRight := float64(x) > float64(r.Max.X)-radius
+	nearTop := float64(y) < float64(r.Min.Y)+radius
+	nearBottom := float64(y) > float64(r.Max.Y)-radius
+
+	var cx, cy float64
+	switch {
+	case nearLeft && nearTop:
+		cx, cy = float64(r.Min.X)+radius, float64(r.Min.Y)+radius
+	case nearRight && nearTop:
+		cx, cy = float64(r.Max.X)-radius, float64(r.Min.Y)+radius
+	case nearLeft && nearBottom:
+		cx, cy = float64(r.Min.X)+radius, float64(r.Max.Y)-radius
+	case nearRight && nearBottom:
+		cx, cy = float64(r.Max.X)-radius, float64(r.Max.Y)-radius
+	default:
+		return true
+	}
+	dx, dy := float64(x)-cx, float64(y)-cy
+	return dx*dx+dy*dy <= radius*radius
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// drawLine draws a straight line from -> to with style.StrokeColor
+// using Bresenham's algorithm.
+func drawLine(dst *image.RGBA, from, to image.Point, style Style) {
+	dx := abs(to.X - from.X)
+	dy := -abs(to.Y - from.Y)
+	sx, sy := sign(to.X-from.X), sign(to.Y-from.Y)
+	err := dx + dy
+	x, y := from.X, from.Y
+	for {
+		dst.Set(x, y, style.StrokeColor)
+		if x == to.X && y == to.Y {
+			break
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y += sy
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,44 @@
+package layoutimg
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestPaintShapeNoFillLeavesBackgroundTransparent(t *testing.T) {
+	bg := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	r := image.Rect(0, 0, 20, 20)
+	center := image.Point{X: 10, Y: 10}
+
+	for _, shape := range []Shape{ShapeRectangle, ShapeEllipse, ShapeDiamond, ShapeRoundedRect} {
+		dst := image.NewRGBA(r)
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				dst.Set(x, y, bg)
+			}
+		}
+
+		paintShape(dst, r, Style{Shape: shape})
+
+		if got := dst.RGBAAt(center.X, center.Y); got != bg {
+			t.Errorf("shape %v: center pixel = %v, want background %v (fill color none must be a no-op)", shape, got, bg)
+		}
+	}
+}
+
+func TestPaintShapeStrokesBorderForEveryShape(t *testing.T) {
+	stroke := color.RGBA{R: 255, A: 255}
+	r := image.Rect(0, 0, 20, 20)
+	style := Style{StrokeColor: stroke, LineWidth: 2}
+
+	for _, shape := range []Shape{ShapeRectangle, ShapeEllipse, ShapeDiamond, ShapeRoundedRect} {
+		dst := image.NewRGBA(r)
+		style.Shape = shape
+		paintShape(dst, r, style)
+
+		if got := dst.RGBAAt(0, 0); got != stroke {
+			t.Errorf("shape %v: top-left border pixel = %v, want stroke color %v", shape, got, stroke)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// Package layoutimg provides a small, declarative API for painting tile
+// grids into images. It is the engine behind the layoutimg CLI, but is
+// usable on its own from any Go program.
+package layoutimg
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// ImageConfig describes the pixel size and background of the image a
+// Grid draws into.
+type ImageConfig struct {
+	Width           int
+	Height          int
+	BackgroundColor color.RGBA
+}
+
+// GridConfig describes how an image is divided into cells.
+//
+// RowHeightOffset and ColumnWidthOffset let individual rows/columns grow
+// or shrink relative to the uniform row height / column width, so grids
+// need not be perfectly regular. A nil slice means every row/column is
+// uniform; otherwise RowHeightOffset[i] (ColumnWidthOffset[i]) is added
+// to the pixel height (width) of row (column) i.
+type GridConfig struct {
+	Column int
+	Row    int
+	Pad    int
+
+	RowHeightOffset   []int
+	ColumnWidthOffset []int
+}
+
+// Style describes how a cell, line, or shape is painted.
+type Style struct {
+	FillColor   color.RGBA
+	StrokeColor color.RGBA
+	LineWidth   float64
+	Shape       Shape
+}
+
+// Shape selects the geometric form PaintCell/PaintCells fills a cell
+// with. The zero value, ShapeRectangle, fills the cell's full bounds.
+type Shape int
+
+const (
+	ShapeRectangle Shape = iota
+	ShapeRoundedRect
+	ShapeEllipse
+	ShapeDiamond
+)
+
+// TileRect is the pixel rectangle and style layoutimg painted for one
+// PaintCell/PaintCells call. Renderers that need vector output (SVG,
+// PDF, ...) can read it back via Grid.Tiles instead of rasterizing.
+type TileRect struct {
+	Min, Max image.Point
+	Style    Style
+}
+
+// Grid is a paintable tile grid backed by an *image.RGBA.
+type Grid struct {
+	img   *image.RGBA
+	image ImageConfig
+	grid  GridConfig
+	tiles []TileRect
+	face  font.Face
+}
+
+// New creates a Grid sized and divided according to image and grid, and
+// fills it with image.BackgroundColor.
+func New(imageCfg ImageConfig, gridCfg GridConfig) *Grid {
+	img := image.NewRGBA(image.Rect(0, 0, imageCfg.Width, imageCfg.Height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{imageCfg.BackgroundColor}, image.Point{}, draw.Src)
+	return &Grid{img: img, image: imageCfg, grid: gridCfg, face: basicfont.Face7x13}
+}
+
+// SetFont overrides the font DrawString uses to render text; pass nil to
+// restore the built-in 7x13 bitmap font.
+func (g *Grid) SetFont(face font.Face) {
+	if face == nil {
+		face = basicfont.Face7x13
+	}
+	g.face = face
+}
+
+// columnEdges returns the pixel x coordinate of the left edge of each
+// column, plus a trailing edge for the right side of the last column.
+func (g *Grid) columnEdges() []int {
+	cellW := g.image.Width / g.grid.Column
+	edges := make([]int, g.grid.Column+1)
+	x := 0
+	for i := 0; i < g.grid.Column; i++ {
+		edges[i] = x
+		w := cellW
+		if i < len(g.grid.ColumnWidthOffset) {
+			w += g.grid.ColumnWidthOffset[i]
+		}
+		x += w
+	}
+	edges[g.grid.Column] = x
+	return edges
+}
+
+// rowEdges is columnEdges for rows.
+func (g *Grid) rowEdges() []int {
+	cellH := g.image.Height / g.grid.Row
+	edges := make([]int, g.grid.Row+1)
+	y := 0
+	for i := 0; i < g.grid.Row; i++ {
+		edges[i] = y
+		h := cellH
+		if i < len(g.grid.RowHeightOffset) {
+			h += g.grid.RowHeightOffset[i]
+		}
+		y += h
+	}
+	edges[g.grid.Row] = y
+	return edges
+}
+
+// cellRect returns the padded pixel rectangle spanning cells
+// [fromRow,fromCol] to [toRow,toCol], inclusive.
+func (g *Grid) cellRect(fromRow, fromCol, toRow, toCol int) image.Rectangle {
+	cols := g.columnEdges()
+	rows := g.rowEdges()
+	pad := g.grid.Pad
+	return image.Rect(
+		cols[fromCol]+pad, rows[fromRow]+pad,
+		cols[toCol+1]-pad, rows[toRow+1]-pad,
+	)
+}
+
+// PaintCell paints the single cell at (row, col) with style.
+func (g *Grid) PaintCell(row, col int, style Style) {
+	g.PaintCells(row, col, row, col, style)
+}
+
+// PaintCells paints the rectangle of cells spanning (fromRow, fromCol)
+// to (toRow, toCol), inclusive, with style.Shape (default
+// ShapeRectangle) in the given style.
+func (g *Grid) PaintCells(fromRow, fromCol, toRow, toCol int, style Style) {
+	r := g.cellRect(fromRow, fromCol, toRow, toCol)
+	paintShape(g.img, r, style)
+	g.tiles = append(g.tiles, TileRect{Min: r.Min, Max: r.Max, Style: style})
+}
+
+// DrawString draws text anchored at the top-left of cell (row, col)
+// using the grid's font (the built-in 7x13 bitmap font by default; see
+// SetFont).
+func (g *Grid) DrawString(row, col int, text string, style Style) {
+	r := g.cellRect(row, col, row, col)
+	d := &font.Drawer{
+		Dst:  g.img,
+		Src:  &image.Uniform{style.FillColor},
+		Face: g.face,
+		Dot:  fixed.P(r.Min.X, r.Min.Y+g.face.Metrics().Height.Ceil()),
+	}
+	d.DrawString(text)
+}
+
+// DrawCircle draws a circle inscribed in cell (row, col).
+func (g *Grid) DrawCircle(row, col int, style Style) {
+	style.Shape = ShapeEllipse
+	g.PaintCell(row, col, style)
+}
+
+// DrawLine draws a straight line between the centers of cell
+// (fromRow, fromCol) and cell (toRow, toCol).
+func (g *Grid) DrawLine(fromRow, fromCol, toRow, toCol int, style Style) {
+	from := center(g.cellRect(fromRow, fromCol, fromRow, fromCol))
+	to := center(g.cellRect(toRow, toCol, toRow, toCol))
+	drawLine(g.img, from, to, style)
+}
+
+// Tiles returns the pixel rectangles and styles painted so far, for
+// renderers that emit vector output instead of rasterizing Image.
+func (g *Grid) Tiles() []TileRect {
+	return g.tiles
+}
+
+// Image returns the grid's underlying raster image.
+func (g *Grid) Image() *image.RGBA {
+	return g.img
+}
+
+// BackgroundColor returns the color New filled the grid with, for
+// renderers that emit vector output and so can't read it back from Image.
+func (g *Grid) BackgroundColor() color.RGBA {
+	return g.image.BackgroundColor
+}
+
+// SavePNG encodes the grid's current image as a PNG to w.
+func (g *Grid) SavePNG(w io.Writer) error {
+	return png.Encode(w, g.img)
+}
+
+func center(r image.Rectangle) image.Point {
+	return image.Point{X: (r.Min.X + r.Max.X) / 2, Y: (r.Min.Y + r.Max.Y) / 2}
+}
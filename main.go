@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"image"
 	"image/color"
-	"image/png"
+	"io"
 	"os"
 	"strconv"
 	"strings"
 
 	"github.com/docopt/docopt-go"
+	"github.com/jiro4989/layoutimg/layoutimg"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
 )
 
 type Config struct {
@@ -24,6 +27,15 @@ type Config struct {
 	FillColor       string   `docopt:"-f,--fill-color"`
 	LineWidth       float64  `docopt:"-l,--line-width"`
 	OutFile         string   `docopt:"-o,--out"`
+	Format          string   `docopt:"--format"`
+	FontFile        string   `docopt:"-F,--font"`
+	Animate         bool     `docopt:"--animate"`
+	Frames          []string `docopt:"--frame"`
+	Delay           int      `docopt:"--delay"`
+	Loop            int      `docopt:"--loop"`
+	ANSI            bool     `docopt:"-T,--ansi"`
+	Columns         int      `docopt:"--columns"`
+	Script          string   `docopt:"--script"`
 	Args            []string `docopt:"<args>"`
 }
 
@@ -34,6 +46,9 @@ const (
 	exitCodeRectangleError
 	exitCodeImageEncodeError
 	exitCodeColorError
+	exitCodeFormatError
+	exitCodeFontError
+	exitCodeScriptError
 )
 
 const version = `tileimg v1.0.0
@@ -45,6 +60,8 @@ const usage = `tileimg draws tile rectangle to image.
 
 Usage:
   tileimg [options] <args>...
+  tileimg --script=<path> [options]
+  tileimg (--frame=<args>)... [options]
   tileimg -h | --help
   tileimg --version
 
@@ -87,6 +104,37 @@ Description:
 
   tleimg fills COLOR to rectangle if <args> is 'COLOR:x,y'.
 
+  tileimg draws a text label inside the rectangle if <args> carries a
+  trailing 'label=TEXT' field, e.g. 'COLOR:x,y:label=Foo'.
+
+  tileimg draws a shape other than a plain rectangle if <args> carries a
+  leading 'SHAPE:' field, where SHAPE is one of rectangle, roundedrect,
+  ellipse, diamond, or circle (an alias for ellipse), e.g.
+  'circle:red:1,1'.
+
+  tileimg reads a layout from a script instead of <args> when --script is
+  given, one statement per line:
+
+    grid W H COLS ROWS PAD
+    bg COLOR
+    stroke COLOR WIDTH
+    fill COLOR X-RANGE Y-RANGE [label TEXT]
+    line R1,C1 R2,C2
+    text R,C "TEXT"
+
+  This avoids argv length limits and per-arg quoting when a layout is too
+  large for a shell command line, e.g. when it's generated by another
+  program.
+
+  tileimg writes an animated GIF instead of a single frame when
+  --animate is given, one frame per --frame flag, each holding the
+  whitespace-separated <args> for that frame, e.g.:
+
+    $ tileimg --frame='0,0' --frame='1,0' --frame='2,0' -o out.gif
+
+  --animate with no --frame flags and plain <args> draws a single-frame
+  animation, e.g. 'tileimg --animate -o out.gif 0,0 1,1'.
+
 Options:
   -h, --help                                   print this help
       --version                                print version
@@ -100,6 +148,27 @@ Options:
   -f, --fill-color=<fill-color>                image file color [default: none]
   -l, --line-width=<line-width>                image line width [default: 2]
   -o, --out=<path>                             out file path
+      --format=<format>                        out file format (png, svg, pdf, jpeg, gif, tiff).
+                                                Inferred from -o's extension, or png, when
+                                                omitted [default: ]
+  -F, --font=<fontfile>                        TTF/OTF font file used to draw labels. Uses the
+                                                built-in 7x13 bitmap font when omitted
+                                                [default: ]
+      --animate                                write an animated GIF instead of a single frame,
+                                                from the single frame in <args>
+      --frame=<args>                           one animation frame's whitespace-separated
+                                                <args>; repeat for each frame. Implies --animate
+      --delay=<delay>                          animation frame delay, in hundredths of
+                                                a second [default: 100]
+      --loop=<loop>                             animation loop count, 0 loops forever
+                                                [default: 0]
+  -T, --ansi                                   print to the terminal with ANSI half-block
+                                                characters instead of encoding an image file
+      --columns=<columns>                      terminal width, in character columns, used by
+                                                '--ansi'; 0 auto-detects it [default: 0]
+      --script=<path>                          read a layoutimg script from path (or stdin if
+                                                path is '-') instead of drawing <args>
+                                                [default: ]
 `
 
 func main() {
@@ -114,7 +183,10 @@ func Main(args []string) int {
 	}
 
 	var config Config
-	opts.Bind(&config)
+	if err := opts.Bind(&config); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitCodeArgsError
+	}
 
 	var w *os.File
 	if config.OutFile == "" {
@@ -129,80 +201,93 @@ func Main(args []string) int {
 		defer w.Close()
 	}
 
-	dest := image.NewRGBA(image.Rect(0, 0, config.Width, config.Height))
-	drawBackground(dest, colors[config.BackgroundColor])
-	bounds := dest.Bounds().Max
-	width := bounds.X
-	height := bounds.Y
-
-	for _, arg := range config.Args {
-		var fillColor color.RGBA
-		var xy string
-		if strings.Contains(arg, ":") {
-			f := strings.Split(arg, ":")
-			var ok bool
-			fillColor, ok = colors[f[0]]
-			if !ok {
-				cols := strings.Split(f[0], ",")
-
-				r, g, b := cols[0], cols[1], cols[2]
-				rr, err := strconv.ParseUint(r, 10, 8)
-				if err != nil {
-					fmt.Fprintln(os.Stderr, err)
-					return exitCodeColorError
-				}
-
-				gg, err := strconv.ParseUint(g, 10, 8)
-				if err != nil {
-					fmt.Fprintln(os.Stderr, err)
-					return exitCodeColorError
-				}
-
-				bb, err := strconv.ParseUint(b, 10, 8)
-				if err != nil {
-					fmt.Fprintln(os.Stderr, err)
-					return exitCodeColorError
-				}
-
-				fillColor = color.RGBA{uint8(rr), uint8(gg), uint8(bb), 255}
+	imageCfg := layoutimg.ImageConfig{
+		Width:           config.Width,
+		Height:          config.Height,
+		BackgroundColor: colors[config.BackgroundColor],
+	}
+	gridCfg := layoutimg.GridConfig{
+		Column: config.Column,
+		Row:    config.Row,
+		Pad:    config.Pad,
+	}
+
+	var face font.Face
+	if config.FontFile != "" {
+		var err error
+		face, err = loadFontFace(config.FontFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitCodeFontError
+		}
+	}
+
+	if config.Script != "" {
+		var r io.Reader = os.Stdin
+		if config.Script != "-" {
+			f, err := os.Open(config.Script)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return exitCodeOpenFileError
 			}
-			xy = f[1]
-		} else {
-			fillColor = colors[config.FillColor]
-			xy = arg
+			defer f.Close()
+			r = f
 		}
-		x, y, x2, y2, err := minMaxXY(xy)
+
+		grid, err := runScript(r, face)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
-			return exitCodeRectangleError
+			return exitCodeScriptError
 		}
 
-		rp := rectangleParam{
-			x:      x,
-			y:      y,
-			column: config.Column,
-			row:    config.Row,
-			width:  width,
-			height: height,
-			pad:    config.Pad,
+		renderer, err := selectRenderer(config)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitCodeFormatError
+		}
+		if err := renderer.Render(w, grid); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitCodeImageEncodeError
+		}
+		return exitCodeOK
+	}
+
+	if config.Animate || len(config.Frames) > 0 {
+		frameArgsList := animationFrames(config)
+		frames := make([]*image.RGBA, 0, len(frameArgsList))
+		for _, frameArgs := range frameArgsList {
+			grid := layoutimg.New(imageCfg, gridCfg)
+			if face != nil {
+				grid.SetFont(face)
+			}
+			if code := paintArgs(grid, frameArgs, config); code != exitCodeOK {
+				return code
+			}
+			frames = append(frames, grid.Image())
 		}
-		r := rectangle(rp)
-
-		rp.x = x2
-		rp.y = y2
-		r2 := rectangle(rp)
-
-		dp := drawParam{
-			min:         r,
-			max:         r2,
-			strokeColor: colors[config.StrokeColor],
-			fillColor:   fillColor,
-			lineWidth:   config.LineWidth,
+
+		if err := encodeAnimated(w, frames, config.Delay, config.Loop); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitCodeImageEncodeError
 		}
-		draw(dest, dp)
+		return exitCodeOK
 	}
 
-	err = png.Encode(w, dest)
+	renderer, err := selectRenderer(config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitCodeFormatError
+	}
+
+	grid := layoutimg.New(imageCfg, gridCfg)
+	if face != nil {
+		grid.SetFont(face)
+	}
+	if code := paintArgs(grid, config.Args, config); code != exitCodeOK {
+		return code
+	}
+
+	err = renderer.Render(w, grid)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return exitCodeImageEncodeError
@@ -211,6 +296,134 @@ func Main(args []string) int {
 	return exitCodeOK
 }
 
+// paintArgs parses each entry of args (see parseArg) and paints it onto
+// grid, returning exitCodeOK or the exit code of the first error.
+func paintArgs(grid *layoutimg.Grid, args []string, config Config) int {
+	for _, arg := range args {
+		shape, fillColor, xy, label, err := parseArg(arg, colors[config.FillColor])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitCodeColorError
+		}
+
+		x, y, x2, y2, err := minMaxXY(xy)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitCodeRectangleError
+		}
+
+		style := layoutimg.Style{
+			FillColor:   fillColor,
+			StrokeColor: colors[config.StrokeColor],
+			LineWidth:   config.LineWidth,
+			Shape:       shape,
+		}
+		grid.PaintCells(y, x, y2, x2, style)
+		if label != "" {
+			grid.DrawString(y, x, label, layoutimg.Style{FillColor: colors[config.StrokeColor]})
+		}
+	}
+	return exitCodeOK
+}
+
+// animationFrames returns the per-frame <args> lists for --animate: one
+// frame per --frame flag, each split on whitespace, when any were
+// given, or the single frame in <args> otherwise.
+func animationFrames(config Config) [][]string {
+	if len(config.Frames) == 0 {
+		return [][]string{config.Args}
+	}
+	frames := make([][]string, len(config.Frames))
+	for i, f := range config.Frames {
+		frames[i] = strings.Fields(f)
+	}
+	return frames
+}
+
+var shapeNames = map[string]layoutimg.Shape{
+	"rectangle":   layoutimg.ShapeRectangle,
+	"roundedrect": layoutimg.ShapeRoundedRect,
+	"ellipse":     layoutimg.ShapeEllipse,
+	"diamond":     layoutimg.ShapeDiamond,
+	"circle":      layoutimg.ShapeEllipse,
+}
+
+// parseArg parses one <args> entry, e.g. 'COLOR:x,y', 'circle:red:1,1',
+// or 'COLOR:x,y:label=Foo'. defaultFillColor is used when arg carries no
+// color field, e.g. plain 'x,y'.
+func parseArg(arg string, defaultFillColor color.RGBA) (shape layoutimg.Shape, fillColor color.RGBA, xy, label string, err error) {
+	parts := strings.Split(arg, ":")
+
+	if s, ok := shapeNames[parts[0]]; ok {
+		shape = s
+		parts = parts[1:]
+	}
+
+	if len(parts) > 0 {
+		if l := parts[len(parts)-1]; strings.HasPrefix(l, "label=") {
+			label = strings.TrimPrefix(l, "label=")
+			parts = parts[:len(parts)-1]
+		}
+	}
+
+	switch len(parts) {
+	case 1:
+		fillColor = defaultFillColor
+		xy = parts[0]
+	case 2:
+		fillColor, err = parseColor(parts[0])
+		xy = parts[1]
+	default:
+		err = fmt.Errorf("invalid args entry: %s", arg)
+	}
+	return
+}
+
+// parseColor resolves s to a color, either a name known to colors or a
+// literal 'r,g,b' triplet.
+func parseColor(s string) (color.RGBA, error) {
+	if c, ok := colors[s]; ok {
+		return c, nil
+	}
+
+	cols := strings.Split(s, ",")
+	if len(cols) != 3 {
+		return color.RGBA{}, fmt.Errorf("unknown color: %s", s)
+	}
+
+	r, g, b := cols[0], cols[1], cols[2]
+	rr, err := strconv.ParseUint(r, 10, 8)
+	if err != nil {
+		return color.RGBA{}, err
+	}
+	gg, err := strconv.ParseUint(g, 10, 8)
+	if err != nil {
+		return color.RGBA{}, err
+	}
+	bb, err := strconv.ParseUint(b, 10, 8)
+	if err != nil {
+		return color.RGBA{}, err
+	}
+	return color.RGBA{uint8(rr), uint8(gg), uint8(bb), 255}, nil
+}
+
+// loadFontFace reads path as a TTF/OTF font and returns a 16pt face for
+// Grid.DrawString.
+func loadFontFace(path string) (font.Face, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := opentype.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return opentype.NewFace(f, &opentype.FaceOptions{
+		Size: 16,
+		DPI:  72,
+	})
+}
+
 func minMaxXY(s string) (x, y, x2, y2 int, err error) {
 	if !strings.Contains(s, ",") {
 		err = errors.New("must need comma separated 2 values")
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnimationFrames(t *testing.T) {
+	cases := []struct {
+		name   string
+		config Config
+		want   [][]string
+	}{
+		{
+			name:   "no --frame flags falls back to the single frame in <args>",
+			config: Config{Args: []string{"0,0", "1,1"}},
+			want:   [][]string{{"0,0", "1,1"}},
+		},
+		{
+			name:   "one frame per --frame flag, split on whitespace",
+			config: Config{Frames: []string{"0,0 1,0", "2,0"}},
+			want:   [][]string{{"0,0", "1,0"}, {"2,0"}},
+		},
+	}
+
+	for _, c := range cases {
+		if got := animationFrames(c.config); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: animationFrames(%+v) = %v, want %v", c.name, c.config, got, c.want)
+		}
+	}
+}
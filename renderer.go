@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/chai2010/tiff"
+	"github.com/jiro4989/layoutimg/layoutimg"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Renderer encodes a rendered Grid to w in a specific output format.
+type Renderer interface {
+	Render(w io.Writer, grid *layoutimg.Grid) error
+}
+
+type pngRenderer struct{}
+
+func (pngRenderer) Render(w io.Writer, grid *layoutimg.Grid) error {
+	return grid.SavePNG(w)
+}
+
+type jpegRenderer struct{}
+
+func (jpegRenderer) Render(w io.Writer, grid *layoutimg.Grid) error {
+	return jpeg.Encode(w, grid.Image(), &jpeg.Options{Quality: jpeg.DefaultQuality})
+}
+
+type gifRenderer struct{}
+
+func (gifRenderer) Render(w io.Writer, grid *layoutimg.Grid) error {
+	return gif.Encode(w, grid.Image(), nil)
+}
+
+type tiffRenderer struct{}
+
+func (tiffRenderer) Render(w io.Writer, grid *layoutimg.Grid) error {
+	return tiff.Encode(w, grid.Image(), nil)
+}
+
+// svgRenderer emits <rect> elements straight from grid.Tiles, so the
+// output stays resolution independent instead of going through a raster
+// buffer.
+type svgRenderer struct{}
+
+func (svgRenderer) Render(w io.Writer, grid *layoutimg.Grid) error {
+	bounds := grid.Image().Bounds()
+	var b strings.Builder
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n",
+		bounds.Dx(), bounds.Dy(), bounds.Dx(), bounds.Dy())
+	fmt.Fprintf(&b, "<rect x=\"0\" y=\"0\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n",
+		bounds.Dx(), bounds.Dy(), hexColor(grid.BackgroundColor()))
+	for _, t := range grid.Tiles() {
+		writeSVGTile(&b, t)
+	}
+	b.WriteString("</svg>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeSVGTile emits the SVG element matching t.Style.Shape, so vector
+// output keeps the shape the raster renderers draw instead of always
+// falling back to a plain rect.
+func writeSVGTile(b *strings.Builder, t layoutimg.TileRect) {
+	fill, stroke, width := hexColor(t.Style.FillColor), hexColor(t.Style.StrokeColor), t.Style.LineWidth
+	x, y := t.Min.X, t.Min.Y
+	w, h := t.Max.X-t.Min.X, t.Max.Y-t.Min.Y
+
+	switch t.Style.Shape {
+	case layoutimg.ShapeEllipse:
+		cx, cy := (t.Min.X+t.Max.X)/2, (t.Min.Y+t.Max.Y)/2
+		fmt.Fprintf(b, "<ellipse cx=\"%d\" cy=\"%d\" rx=\"%d\" ry=\"%d\" fill=\"%s\" stroke=\"%s\" stroke-width=\"%g\"/>\n",
+			cx, cy, w/2, h/2, fill, stroke, width)
+	case layoutimg.ShapeDiamond:
+		cx, cy := (t.Min.X+t.Max.X)/2, (t.Min.Y+t.Max.Y)/2
+		fmt.Fprintf(b, "<polygon points=\"%d,%d %d,%d %d,%d %d,%d\" fill=\"%s\" stroke=\"%s\" stroke-width=\"%g\"/>\n",
+			cx, t.Min.Y, t.Max.X, cy, cx, t.Max.Y, t.Min.X, cy, fill, stroke, width)
+	case layoutimg.ShapeRoundedRect:
+		radius := w
+		if h < radius {
+			radius = h
+		}
+		radius /= 4
+		fmt.Fprintf(b, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" rx=\"%d\" ry=\"%d\" fill=\"%s\" stroke=\"%s\" stroke-width=\"%g\"/>\n",
+			x, y, w, h, radius, radius, fill, stroke, width)
+	default:
+		fmt.Fprintf(b, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\" stroke=\"%s\" stroke-width=\"%g\"/>\n",
+			x, y, w, h, fill, stroke, width)
+	}
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// pdfRenderer embeds the same tile rectangles into a single page PDF via
+// a lightweight vector backend, so layouts can be dropped straight into
+// documentation.
+type pdfRenderer struct{}
+
+func (pdfRenderer) Render(w io.Writer, grid *layoutimg.Grid) error {
+	bounds := grid.Image().Bounds()
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		UnitStr: "pt",
+		Size:    gofpdf.SizeType{Wd: float64(bounds.Dx()), Ht: float64(bounds.Dy())},
+	})
+	pdf.AddPage()
+
+	bg := grid.BackgroundColor()
+	pdf.SetFillColor(int(bg.R), int(bg.G), int(bg.B))
+	pdf.Rect(0, 0, float64(bounds.Dx()), float64(bounds.Dy()), "F")
+
+	for _, t := range grid.Tiles() {
+		pdf.SetFillColor(int(t.Style.FillColor.R), int(t.Style.FillColor.G), int(t.Style.FillColor.B))
+		pdf.SetDrawColor(int(t.Style.StrokeColor.R), int(t.Style.StrokeColor.G), int(t.Style.StrokeColor.B))
+		pdf.SetLineWidth(t.Style.LineWidth)
+		writePDFTile(pdf, t)
+	}
+
+	return pdf.Output(w)
+}
+
+// writePDFTile draws the gofpdf shape matching t.Style.Shape, so PDF
+// output keeps the shape the raster renderers draw instead of always
+// falling back to a plain rect.
+func writePDFTile(pdf *gofpdf.Fpdf, t layoutimg.TileRect) {
+	x, y := float64(t.Min.X), float64(t.Min.Y)
+	width, height := float64(t.Max.X-t.Min.X), float64(t.Max.Y-t.Min.Y)
+
+	switch t.Style.Shape {
+	case layoutimg.ShapeEllipse:
+		pdf.Ellipse(x+width/2, y+height/2, width/2, height/2, 0, "FD")
+	case layoutimg.ShapeDiamond:
+		cx, cy := x+width/2, y+height/2
+		pdf.Polygon([]gofpdf.PointType{
+			{X: cx, Y: y},
+			{X: x + width, Y: cy},
+			{X: cx, Y: y + height},
+			{X: x, Y: cy},
+		}, "FD")
+	case layoutimg.ShapeRoundedRect:
+		radius := width
+		if height < radius {
+			radius = height
+		}
+		radius /= 4
+		pdf.RoundedRect(x, y, width, height, radius, "1234", "FD")
+	default:
+		pdf.Rect(x, y, width, height, "FD")
+	}
+}
+
+// selectRenderer picks a Renderer for config, preferring --ansi over
+// --format/the -o file extension.
+func selectRenderer(config Config) (Renderer, error) {
+	if config.ANSI {
+		return ansiRenderer{columns: config.Columns}, nil
+	}
+	return rendererFor(config.Format, config.OutFile)
+}
+
+// rendererFor picks a Renderer for format, falling back to the file
+// extension of path when format is empty.
+func rendererFor(format, path string) (Renderer, error) {
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+	switch strings.ToLower(format) {
+	case "", "png":
+		return pngRenderer{}, nil
+	case "jpg", "jpeg":
+		return jpegRenderer{}, nil
+	case "gif":
+		return gifRenderer{}, nil
+	case "tif", "tiff":
+		return tiffRenderer{}, nil
+	case "svg":
+		return svgRenderer{}, nil
+	case "pdf":
+		return pdfRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported output format: %s", format)
+	}
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jiro4989/layoutimg/layoutimg"
+	"golang.org/x/image/font"
+)
+
+// gridParams holds the fields of a script's 'grid' statement, read
+// before the Grid itself can be constructed.
+type gridParams struct {
+	width, height, column, row, pad int
+}
+
+// runScript reads a line-oriented layoutimg script from r and paints it
+// onto a freshly constructed Grid, for layouts too large to fit on a
+// shell command line. face, if non-nil, is used to render 'text'
+// statements.
+//
+// Grammar (one statement per line; blank lines and '#' comments are
+// ignored):
+//
+//	grid W H COLS ROWS PAD
+//	bg COLOR
+//	stroke COLOR WIDTH
+//	fill COLOR X-RANGE Y-RANGE [label TEXT]
+//	line R1,C1 R2,C2
+//	text R,C "TEXT"
+//
+// 'grid' must appear exactly once, before 'fill', 'line', or 'text'.
+// 'bg' and 'stroke' set the background/stroke used by statements that
+// follow them.
+func runScript(r io.Reader, face font.Face) (*layoutimg.Grid, error) {
+	var params *gridParams
+	var grid *layoutimg.Grid
+	bg := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	stroke := color.RGBA{A: 255}
+	lineWidth := 2.0
+
+	ensureGrid := func() (*layoutimg.Grid, error) {
+		if grid != nil {
+			return grid, nil
+		}
+		if params == nil {
+			return nil, errors.New("no grid statement yet")
+		}
+		grid = layoutimg.New(
+			layoutimg.ImageConfig{Width: params.width, Height: params.height, BackgroundColor: bg},
+			layoutimg.GridConfig{Column: params.column, Row: params.row, Pad: params.pad},
+		)
+		if face != nil {
+			grid.SetFont(face)
+		}
+		return grid, nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		tokens, err := tokenizeScriptLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+
+		switch tokens[0] {
+		case "grid":
+			if params != nil {
+				return nil, fmt.Errorf("line %d: duplicate grid statement", lineNo)
+			}
+			params, err = parseGridStmt(tokens[1:])
+
+		case "bg":
+			if grid != nil {
+				err = errors.New("bg must come before the first fill/line/text statement")
+			} else if len(tokens) != 2 {
+				err = errors.New("bg wants 1 argument: COLOR")
+			} else {
+				bg, err = parseColor(tokens[1])
+			}
+
+		case "stroke":
+			if len(tokens) != 3 {
+				err = errors.New("stroke wants 2 arguments: COLOR WIDTH")
+				break
+			}
+			stroke, err = parseColor(tokens[1])
+			if err == nil {
+				lineWidth, err = strconv.ParseFloat(tokens[2], 64)
+			}
+
+		case "fill":
+			err = runFillStmt(ensureGrid, tokens[1:], stroke, lineWidth)
+
+		case "line":
+			err = runLineStmt(ensureGrid, tokens[1:], stroke, lineWidth)
+
+		case "text":
+			err = runTextStmt(ensureGrid, tokens[1:], stroke)
+
+		default:
+			err = fmt.Errorf("unknown statement %q", tokens[0])
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return ensureGrid()
+}
+
+func parseGridStmt(tokens []string) (*gridParams, error) {
+	if len(tokens) != 5 {
+		return nil, errors.New("grid wants 5 arguments: W H COLS ROWS PAD")
+	}
+	ints := make([]int, 5)
+	for i, t := range tokens {
+		n, err := strconv.Atoi(t)
+		if err != nil {
+			return nil, err
+		}
+		ints[i] = n
+	}
+	return &gridParams{width: ints[0], height: ints[1], column: ints[2], row: ints[3], pad: ints[4]}, nil
+}
+
+func runFillStmt(ensureGrid func() (*layoutimg.Grid, error), tokens []string, stroke color.RGBA, lineWidth float64) error {
+	if len(tokens) < 3 {
+		return errors.New("fill wants at least 3 arguments: COLOR X-RANGE Y-RANGE [label TEXT]")
+	}
+	fillColor, err := parseColor(tokens[0])
+	if err != nil {
+		return err
+	}
+	x1, x2, err := splitHyphen(tokens[1])
+	if err != nil {
+		return err
+	}
+	y1, y2, err := splitHyphen(tokens[2])
+	if err != nil {
+		return err
+	}
+
+	var label string
+	if len(tokens) > 3 {
+		if tokens[3] != "label" {
+			return fmt.Errorf("unknown fill option %q", tokens[3])
+		}
+		label = strings.Join(tokens[4:], " ")
+	}
+
+	grid, err := ensureGrid()
+	if err != nil {
+		return err
+	}
+	grid.PaintCells(y1, x1, y2, x2, layoutimg.Style{FillColor: fillColor, StrokeColor: stroke, LineWidth: lineWidth})
+	if label != "" {
+		grid.DrawString(y1, x1, label, layoutimg.Style{FillColor: stroke})
+	}
+	return nil
+}
+
+func runLineStmt(ensureGrid func() (*layoutimg.Grid, error), tokens []string, stroke color.RGBA, lineWidth float64) error {
+	if len(tokens) != 2 {
+		return errors.New("line wants 2 arguments: R1,C1 R2,C2")
+	}
+	r1, c1, err := parsePoint(tokens[0])
+	if err != nil {
+		return err
+	}
+	r2, c2, err := parsePoint(tokens[1])
+	if err != nil {
+		return err
+	}
+
+	grid, err := ensureGrid()
+	if err != nil {
+		return err
+	}
+	grid.DrawLine(r1, c1, r2, c2, layoutimg.Style{StrokeColor: stroke, LineWidth: lineWidth})
+	return nil
+}
+
+func runTextStmt(ensureGrid func() (*layoutimg.Grid, error), tokens []string, stroke color.RGBA) error {
+	if len(tokens) != 2 {
+		return errors.New("text wants 2 arguments: R,C \"TEXT\"")
+	}
+	row, col, err := parsePoint(tokens[0])
+	if err != nil {
+		return err
+	}
+
+	grid, err := ensureGrid()
+	if err != nil {
+		return err
+	}
+	grid.DrawString(row, col, tokens[1], layoutimg.Style{FillColor: stroke})
+	return nil
+}
+
+// parsePoint parses "R,C" into row, col.
+func parsePoint(s string) (row, col int, err error) {
+	fs := strings.SplitN(s, ",", 2)
+	if len(fs) != 2 {
+		return 0, 0, fmt.Errorf("%q is not a R,C point", s)
+	}
+	row, err = strconv.Atoi(fs[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	col, err = strconv.Atoi(fs[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return row, col, nil
+}
+
+// tokenizeScriptLine splits line on whitespace, treating a "..."
+// substring as a single token so 'text R,C "some text"' keeps its
+// spaces.
+func tokenizeScriptLine(line string) ([]string, error) {
+	var tokens []string
+	var cur strings.Builder
+	inQuotes := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '"':
+			inQuotes = !inQuotes
+		case c == ' ' && !inQuotes:
+			if cur.Len() > 0 {
+				tokens = append(tokens, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	if inQuotes {
+		return nil, errors.New("unterminated quoted string")
+	}
+	if cur.Len() > 0 {
+		tokens = append(tokens, cur.String())
+	}
+	return tokens, nil
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunScriptMissingGridIsNotDoubleWrapped(t *testing.T) {
+	_, err := runScript(strings.NewReader("fill red 0-0 0-0\n"), nil)
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+	want := "line 1: no grid statement yet"
+	if err.Error() != want {
+		t.Errorf("err = %q, want %q", err.Error(), want)
+	}
+}